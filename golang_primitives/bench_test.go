@@ -2,149 +2,208 @@ package benchs
 
 import (
 	"runtime/debug"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 // Benchmark: Mutex-protected ring buffer pool
 func Benchmark_MutexRingBufferPool(b *testing.B) {
-	debug.SetGCPercent(-1)
-	b.ReportAllocs()
-
 	pool := NewMutexRingBufferPool(1000, testAllocator, testCleaner)
+	runPoolLatencyBenchmark(b, pool, latencyWorkload{})
+}
 
-	b.ResetTimer()
-	b.RunParallel(func(pb *testing.PB) {
-		for pb.Next() {
-			obj := pool.Get()
-			pool.Put(obj)
-		}
-	})
+// Benchmark: Mutex-protected ring buffer pool under simulated hold time and
+// an imbalanced Get/Put split - the case that exposes long tails that a
+// plain throughput number hides.
+func Benchmark_MutexRingBufferPool_Contended(b *testing.B) {
+	pool := NewMutexRingBufferPool(1000, testAllocator, testCleaner)
+	runPoolLatencyBenchmark(b, pool, latencyWorkload{HoldTime: time.Microsecond, Imbalance: true})
 }
 
 // Benchmark: Channel-based pool
 func Benchmark_ChannelBasedPool(b *testing.B) {
-	debug.SetGCPercent(-1)
-	b.ReportAllocs()
-
 	pool := NewChannelBasedPool(1000, testAllocator, testCleaner)
-
-	b.ResetTimer()
-	b.RunParallel(func(pb *testing.PB) {
-		for pb.Next() {
-			obj := pool.Get()
-			pool.Put(obj)
-		}
-	})
+	runPoolLatencyBenchmark(b, pool, latencyWorkload{})
 }
 
 // Benchmark: Atomic-based pool
 func Benchmark_AtomicBasedPool(b *testing.B) {
-	debug.SetGCPercent(-1)
-	b.ReportAllocs()
-
 	pool := NewAtomicBasedPool(1000, testAllocator, testCleaner)
-
-	b.ResetTimer()
-	b.RunParallel(func(pb *testing.PB) {
-		for pb.Next() {
-			obj := pool.Get()
-			pool.Put(obj)
-		}
-	})
+	runPoolLatencyBenchmark(b, pool, latencyWorkload{})
 }
 
 // Benchmark: Ring buffer with condition variables pool
 func Benchmark_RingBufferCondPool(b *testing.B) {
-	debug.SetGCPercent(-1)
-	b.ReportAllocs()
-
 	pool := NewRingBufferCondPool(1000, testAllocator, testCleaner)
-
-	b.ResetTimer()
-	b.RunParallel(func(pb *testing.PB) {
-		for pb.Next() {
-			obj := pool.Get()
-			pool.Put(obj)
-		}
-	})
+	runPoolLatencyBenchmark(b, pool, latencyWorkload{})
 }
 
 // Benchmark: Sharded Mutex Ring Buffer Pool
 func Benchmark_ShardedMutexRingBufferPool(b *testing.B) {
-	debug.SetGCPercent(-1)
-	b.ReportAllocs()
-
 	pool := NewShardedMutexRingBufferPool(1000, 0, testAllocator, testCleaner)
+	runPoolLatencyBenchmark(b, pool, latencyWorkload{})
+}
 
-	b.ResetTimer()
-	b.RunParallel(func(pb *testing.PB) {
-		for pb.Next() {
-			obj := pool.Get()
-			pool.Put(obj)
-		}
-	})
+// Benchmark: Sharded Mutex Ring Buffer Pool under an imbalanced Get/Put
+// split: objects are put back from a different P than the one that drew
+// them, while Put still routes each to the shard recorded in its shardIndex.
+func Benchmark_ShardedMutexRingBufferPool_Imbalanced(b *testing.B) {
+	pool := NewShardedMutexRingBufferPool(1000, 0, testAllocator, testCleaner)
+	runPoolLatencyBenchmark(b, pool, latencyWorkload{Imbalance: true})
 }
 
 // Benchmark: Sharded Channel-based pool
 func Benchmark_ShardedChannelBasedPool(b *testing.B) {
+	pool := NewShardedChannelBasedPool(1000, 0, testAllocator, testCleaner)
+	runPoolLatencyBenchmark(b, pool, latencyWorkload{})
+}
+
+// Benchmark: Sharded Atomic-based pool
+func Benchmark_ShardedAtomicBasedPool(b *testing.B) {
+	pool := NewShardedAtomicBasedPool(1000, 0, testAllocator, testCleaner)
+	runPoolLatencyBenchmark(b, pool, latencyWorkload{})
+}
+
+// Benchmark: Sharded Ring buffer with condition variables pool
+func Benchmark_ShardedRingBufferCondPool(b *testing.B) {
+	pool := NewShardedRingBufferCondPool(1000, 0, testAllocator, testCleaner)
+	runPoolLatencyBenchmark(b, pool, latencyWorkload{})
+}
+
+// Benchmark: Local cache pool with batched global overflow
+func Benchmark_LocalCachePool(b *testing.B) {
+	pool := NewLocalCachePool(128, 32, testAllocator, testCleaner)
+	runPoolLatencyBenchmark(b, pool, latencyWorkload{})
+}
+
+// Benchmark: Lock-free work-stealing deque pool
+func Benchmark_LockFreeDequePool(b *testing.B) {
+	pool := NewLockFreeDequePool(1000, 0, testAllocator, testCleaner)
+	runPoolLatencyBenchmark(b, pool, latencyWorkload{})
+}
+
+// Benchmark: Lock-free work-stealing deque pool under an imbalanced
+// Get/Put split, which forces constant stealing from neighboring shards.
+func Benchmark_LockFreeDequePool_Imbalanced(b *testing.B) {
+	pool := NewLockFreeDequePool(1000, 0, testAllocator, testCleaner)
+	runPoolLatencyBenchmark(b, pool, latencyWorkload{Imbalance: true})
+}
+
+// Benchmark: Size-classed []byte pool with mixed request sizes
+func Benchmark_SizeClassedBufferPool(b *testing.B) {
 	debug.SetGCPercent(-1)
 	b.ReportAllocs()
 
-	pool := NewShardedChannelBasedPool(1000, 0, testAllocator, testCleaner)
+	pool := NewSizeClassedBufferPool([]int{64, 256, 1024, 4096}, 256, 0)
+	defer pool.Close()
+
+	sizes := []int{32, 100, 500, 2000, 4096}
+
+	var wasted, ops atomic.Int64
 
 	b.ResetTimer()
 	b.RunParallel(func(pb *testing.PB) {
+		i := 0
 		for pb.Next() {
-			obj := pool.Get()
-			pool.Put(obj)
+			n := sizes[i%len(sizes)]
+			buf := pool.Get(n)
+			wasted.Add(int64(cap(buf) - n))
+			ops.Add(1)
+			i++
+			pool.Put(buf)
 		}
 	})
+
+	if n := ops.Load(); n > 0 {
+		b.ReportMetric(float64(wasted.Load())/float64(n), "wasted-bytes/op")
+	}
 }
 
-// Benchmark: Sharded Atomic-based pool
-func Benchmark_ShardedAtomicBasedPool(b *testing.B) {
+// Benchmark: a single fixed-capacity []byte pool with no size classes,
+// driven with the same mixed request sizes as Benchmark_SizeClassedBufferPool.
+// This is the baseline Benchmark_SizeClassedBufferPool is meant to beat:
+// every buffer here is sized for the largest request in the workload, so
+// anything smaller wastes the difference instead of getting a right-sized
+// buffer back - see the wasted-bytes/op metric on both benchmarks.
+func Benchmark_FixedSizeBufferPool(b *testing.B) {
 	debug.SetGCPercent(-1)
 	b.ReportAllocs()
 
-	pool := NewShardedAtomicBasedPool(1000, 0, testAllocator, testCleaner)
+	const fixedCapacity = 4096
+	pool := newMutexRingByteClass(256, fixedCapacity)
+
+	sizes := []int{32, 100, 500, 2000, 4096}
+
+	var wasted, ops atomic.Int64
 
 	b.ResetTimer()
 	b.RunParallel(func(pb *testing.PB) {
+		i := 0
 		for pb.Next() {
-			obj := pool.Get()
-			pool.Put(obj)
+			n := sizes[i%len(sizes)]
+
+			buf, ok := pool.get()
+			if !ok {
+				buf = make([]byte, 0, fixedCapacity)
+			}
+			wasted.Add(int64(cap(buf) - n))
+			ops.Add(1)
+			i++
+			pool.put(buf)
 		}
 	})
+
+	if n := ops.Load(); n > 0 {
+		b.ReportMetric(float64(wasted.Load())/float64(n), "wasted-bytes/op")
+	}
 }
 
-// Benchmark: Sharded Ring buffer with condition variables pool
-func Benchmark_ShardedRingBufferCondPool(b *testing.B) {
+// Benchmark: generic Pool[[]int] vs sync.Pool storing []int. sync.Pool boxes
+// the slice header into an interface{} on every Put; Pool[T] stores it
+// directly, so this benchmark should show fewer allocations.
+func Benchmark_GenericPool_Slice(b *testing.B) {
 	debug.SetGCPercent(-1)
 	b.ReportAllocs()
 
-	pool := NewShardedRingBufferCondPool(1000, 0, testAllocator, testCleaner)
+	pool := NewPool[[]int](MutexRingBufferKind, 1000, func() []int { return make([]int, 0, 16) }, nil)
 
 	b.ResetTimer()
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
-			obj := pool.Get()
-			pool.Put(obj)
+			s := pool.Get()
+			s = append(s, 1, 2, 3)
+			pool.Put(s[:0])
 		}
 	})
 }
 
-// Benchmark: Sharded Goroutine ID Pool (no proc pinning, no shard index storage)
-func Benchmark_ShardedGoroutineIDPool(b *testing.B) {
+func Benchmark_SyncPool_Slice(b *testing.B) {
 	debug.SetGCPercent(-1)
 	b.ReportAllocs()
 
-	pool := NewShardedGoroutineIDPool(1000, 0, testAllocator, testCleaner)
+	pool := sync.Pool{New: func() any { return make([]int, 0, 16) }}
 
 	b.ResetTimer()
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
-			obj := pool.Get()
-			pool.Put(obj)
+			s := pool.Get().([]int)
+			s = append(s, 1, 2, 3)
+			pool.Put(s[:0])
 		}
 	})
 }
+
+// Benchmark: Sharded Mutex Ring Buffer Pool with the GC victim cache enabled
+func Benchmark_ShardedMutexRingBufferPool_GCVictimCache(b *testing.B) {
+	pool := NewShardedMutexRingBufferPool(1000, 0, testAllocator, testCleaner)
+	pool.EnableGCVictimCache()
+	runPoolLatencyBenchmark(b, pool, latencyWorkload{})
+}
+
+// Benchmark: Sharded Goroutine ID Pool (no proc pinning, no shard index storage)
+func Benchmark_ShardedGoroutineIDPool(b *testing.B) {
+	pool := NewShardedGoroutineIDPool(1000, 0, testAllocator, testCleaner)
+	runPoolLatencyBenchmark(b, pool, latencyWorkload{})
+}
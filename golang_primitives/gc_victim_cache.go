@@ -0,0 +1,126 @@
+package benchs
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// gcTicker invokes fn once per garbage collection cycle by re-arming a
+// runtime.SetFinalizer on a throwaway sentinel every time it fires - the
+// standard trick for observing GC cycles without a dedicated runtime hook.
+type gcTicker struct {
+	stopped atomic.Bool
+}
+
+// gcSentinel must not be zero-size: runtime.SetFinalizer explicitly does not
+// guarantee finalizers run on zero-size allocations, since they may share an
+// address with every other zero-size value in the program.
+type gcSentinel struct{ _ int }
+
+func newGCTicker(fn func()) *gcTicker {
+	t := &gcTicker{}
+	t.arm(fn)
+	return t
+}
+
+func (t *gcTicker) arm(fn func()) {
+	runtime.SetFinalizer(new(gcSentinel), func(*gcSentinel) {
+		if t.stopped.Load() {
+			return
+		}
+		fn()
+		t.arm(fn)
+	})
+}
+
+// Close stops fn from being invoked on future GC cycles.
+func (t *gcTicker) Close() {
+	t.stopped.Store(true)
+}
+
+// EnableGCVictimCache turns the pool into a two-generation, sync.Pool-style
+// cache: on each GC cycle, every shard's available objects are moved into a
+// per-shard victim buffer and whatever was previously in that victim is
+// dropped. Get checks the primary shard first, then the victim, before
+// falling back to the allocator; Put always goes to the primary. This
+// bounds live pooled memory to at most two GC cycles' worth of objects,
+// unlike the pool's default fixed-capacity behavior.
+func (p *ShardedMutexRingBufferPool) EnableGCVictimCache() {
+	if p.ticker != nil {
+		return
+	}
+
+	p.victims = make([]atomic.Pointer[MutexRingBufferPool], len(p.shards))
+
+	p.ticker = newGCTicker(func() {
+		for i, shard := range p.shards {
+			drained := shard.drain()
+			if len(drained) == 0 {
+				p.victims[i].Store(nil)
+				continue
+			}
+
+			rb := NewRingBuffer[*testObject](len(drained))
+			for _, obj := range drained {
+				rb.Push(obj)
+			}
+
+			p.victims[i].Store(&MutexRingBufferPool{
+				ringBuffer: rb,
+				allocator:  shard.allocator,
+				cleaner:    shard.cleaner,
+			})
+		}
+	})
+}
+
+// DisableGCVictimCache stops the GC ticker armed by EnableGCVictimCache. The
+// ticker's finalizer closure captures p and re-arms itself on every GC cycle,
+// so without this, a pool that ever called EnableGCVictimCache can never
+// become unreachable - calling this before dropping the last reference to p
+// is required to let it be collected. It's a no-op if the victim cache was
+// never enabled.
+func (p *ShardedMutexRingBufferPool) DisableGCVictimCache() {
+	if p.ticker == nil {
+		return
+	}
+	p.ticker.Close()
+}
+
+// EnableGCVictimCache is the ShardedAtomicBasedPool counterpart of
+// ShardedMutexRingBufferPool.EnableGCVictimCache; see its doc comment.
+func (p *ShardedAtomicBasedPool) EnableGCVictimCache() {
+	if p.ticker != nil {
+		return
+	}
+
+	p.victims = make([]atomic.Pointer[AtomicBasedPool], len(p.shards))
+
+	p.ticker = newGCTicker(func() {
+		for i, shard := range p.shards {
+			drained := shard.drain()
+			if len(drained) == 0 {
+				p.victims[i].Store(nil)
+				continue
+			}
+
+			victim := &AtomicBasedPool{
+				objects:   drained,
+				capacity:  int64(len(drained)),
+				allocator: shard.allocator,
+				cleaner:   shard.cleaner,
+			}
+			victim.index.Store(int64(len(drained)))
+			p.victims[i].Store(victim)
+		}
+	})
+}
+
+// DisableGCVictimCache is the ShardedAtomicBasedPool counterpart of
+// ShardedMutexRingBufferPool.DisableGCVictimCache; see its doc comment.
+func (p *ShardedAtomicBasedPool) DisableGCVictimCache() {
+	if p.ticker == nil {
+		return
+	}
+	p.ticker.Close()
+}
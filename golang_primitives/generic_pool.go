@@ -0,0 +1,182 @@
+package benchs
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Pool is a generic alternative to sync.Pool: Get/Put hold T directly
+// instead of going through the interface{} boxing round-trip that
+// sync.Pool forces on every value, so pooling a slice header or other
+// non-pointer T doesn't allocate on Put.
+type Pool[T any] interface {
+	Get() T
+	Put(v T)
+}
+
+// NewPool builds a Pool[T] of capacity, backed by the implementation
+// selected by impl. alloc supplies a fresh T when the pool is empty;
+// clean, if non-nil, is run on a value before it re-enters the pool.
+func NewPool[T any](impl PoolKind, capacity int, alloc func() T, clean func(T)) Pool[T] {
+	switch impl {
+	case ChannelKind:
+		p := &genericChannelPool[T]{ch: make(chan T, capacity), alloc: alloc, clean: clean}
+		for range capacity {
+			p.ch <- alloc()
+		}
+		return p
+	case AtomicIndexKind:
+		p := &genericAtomicPool[T]{objects: make([]T, capacity), capacity: int64(capacity), alloc: alloc, clean: clean}
+		for i := range p.objects {
+			p.objects[i] = alloc()
+		}
+		p.index.Store(int64(capacity))
+		return p
+	case CondKind:
+		p := &genericCondPool[T]{objects: make([]T, 0, capacity), clean: clean}
+		p.cond = sync.NewCond(&p.mu)
+		for range capacity {
+			p.objects = append(p.objects, alloc())
+		}
+		return p
+	default:
+		p := &genericMutexRingPool[T]{rb: NewRingBuffer[T](capacity), alloc: alloc, clean: clean}
+		for range capacity {
+			p.rb.Push(alloc())
+		}
+		return p
+	}
+}
+
+// genericMutexRingPool backs a Pool[T] with a mutex-protected RingBuffer[T],
+// mirroring MutexRingBufferPool but holding T directly instead of *testObject.
+type genericMutexRingPool[T any] struct {
+	rb    *RingBuffer[T]
+	mu    sync.Mutex
+	alloc func() T
+	clean func(T)
+}
+
+func (p *genericMutexRingPool[T]) Get() T {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if v, ok := p.rb.Pop(); ok {
+		return v
+	}
+	return p.alloc()
+}
+
+func (p *genericMutexRingPool[T]) Put(v T) {
+	if p.clean != nil {
+		p.clean(v)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rb.Push(v)
+}
+
+// genericChannelPool backs a Pool[T] with a buffered channel, mirroring
+// ChannelBasedPool but holding T directly instead of *testObject.
+type genericChannelPool[T any] struct {
+	ch    chan T
+	alloc func() T
+	clean func(T)
+}
+
+func (p *genericChannelPool[T]) Get() T {
+	select {
+	case v := <-p.ch:
+		return v
+	default:
+		return p.alloc()
+	}
+}
+
+func (p *genericChannelPool[T]) Put(v T) {
+	if p.clean != nil {
+		p.clean(v)
+	}
+
+	// Select isn't used because we don't want to drop values.
+	p.ch <- v
+}
+
+// genericAtomicPool backs a Pool[T] with a CAS-protected index over a fixed
+// slice, mirroring AtomicBasedPool but holding T directly instead of
+// *testObject.
+type genericAtomicPool[T any] struct {
+	objects  []T
+	index    atomic.Int64
+	capacity int64
+	alloc    func() T
+	clean    func(T)
+}
+
+func (p *genericAtomicPool[T]) Get() T {
+	for {
+		idx := p.index.Load()
+		if idx <= 0 {
+			return p.alloc()
+		}
+		if p.index.CompareAndSwap(idx, idx-1) {
+			return p.objects[idx-1]
+		}
+	}
+}
+
+func (p *genericAtomicPool[T]) Put(v T) {
+	if p.clean != nil {
+		p.clean(v)
+	}
+
+	for {
+		idx := p.index.Load()
+		if idx >= p.capacity {
+			return
+		}
+		if p.index.CompareAndSwap(idx, idx+1) {
+			p.objects[idx] = v
+			return
+		}
+	}
+}
+
+// genericCondPool backs a Pool[T] with a condition variable, mirroring
+// CondBasedPool but holding T directly instead of *testObject. Get blocks
+// until a value is available rather than falling back to alloc.
+type genericCondPool[T any] struct {
+	objects []T
+	mu      sync.Mutex
+	cond    *sync.Cond
+	clean   func(T)
+}
+
+func (p *genericCondPool[T]) Get() T {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for len(p.objects) == 0 {
+		p.cond.Wait()
+	}
+
+	v := p.objects[len(p.objects)-1]
+	p.objects = p.objects[:len(p.objects)-1]
+	return v
+}
+
+func (p *genericCondPool[T]) Put(v T) {
+	if p.clean != nil {
+		p.clean(v)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.objects) < cap(p.objects) {
+		p.objects = append(p.objects, v)
+		p.cond.Signal()
+	}
+}
@@ -92,14 +92,33 @@ func NewMutexRingBufferPool(capacity int, allocator func() *testObject, cleaner
 }
 
 func (p *MutexRingBufferPool) Get() *testObject {
+	if obj, ok := p.tryGet(); ok {
+		return obj
+	}
+	return p.allocator()
+}
+
+// tryGet pops from the ring buffer without falling back to the allocator.
+func (p *MutexRingBufferPool) tryGet() (*testObject, bool) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	if obj, ok := p.ringBuffer.Pop(); ok {
-		return obj
-	}
+	return p.ringBuffer.Pop()
+}
 
-	return p.allocator()
+// drain empties the ring buffer and returns everything it held.
+func (p *MutexRingBufferPool) drain() []*testObject {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var drained []*testObject
+	for {
+		obj, ok := p.ringBuffer.Pop()
+		if !ok {
+			return drained
+		}
+		drained = append(drained, obj)
+	}
 }
 
 func (p *MutexRingBufferPool) Put(obj *testObject) {
@@ -221,17 +240,38 @@ func NewAtomicBasedPool(capacity int, allocator func() *testObject, cleaner func
 }
 
 func (p *AtomicBasedPool) Get() *testObject {
+	if obj, ok := p.tryGet(); ok {
+		return obj
+	}
+	return p.allocator()
+}
+
+// tryGet pops from the index-protected slice without falling back to the
+// allocator.
+func (p *AtomicBasedPool) tryGet() (*testObject, bool) {
 	for {
 		idx := p.index.Load()
 		if idx <= 0 {
-			return p.allocator()
+			return nil, false
 		}
 		if p.index.CompareAndSwap(idx, idx-1) {
-			return p.objects[idx-1]
+			return p.objects[idx-1], true
 		}
 	}
 }
 
+// drain pops everything currently available.
+func (p *AtomicBasedPool) drain() []*testObject {
+	var drained []*testObject
+	for {
+		obj, ok := p.tryGet()
+		if !ok {
+			return drained
+		}
+		drained = append(drained, obj)
+	}
+}
+
 func (p *AtomicBasedPool) Put(obj *testObject) {
 	for {
 		idx := p.index.Load()
@@ -349,6 +389,10 @@ func (p *RingBufferCondPool) Put(obj *testObject) {
 // Sharded Mutex Ring Buffer Pool
 type ShardedMutexRingBufferPool struct {
 	shards []*MutexRingBufferPool
+
+	// victims and ticker are only set once EnableGCVictimCache has been called.
+	victims []atomic.Pointer[MutexRingBufferPool]
+	ticker  *gcTicker
 }
 
 func NewShardedMutexRingBufferPool(capacity int, numShards int, allocator func() *testObject, cleaner func(*testObject)) *ShardedMutexRingBufferPool {
@@ -373,7 +417,21 @@ func (p *ShardedMutexRingBufferPool) Get() *testObject {
 	shard := p.shards[shardIndex]
 	runtimeProcUnpin()
 
-	obj := shard.Get()
+	if obj, ok := shard.tryGet(); ok {
+		obj.shardIndex = shardIndex
+		return obj
+	}
+
+	if p.victims != nil {
+		if victim := p.victims[shardIndex].Load(); victim != nil {
+			if obj, ok := victim.tryGet(); ok {
+				obj.shardIndex = shardIndex
+				return obj
+			}
+		}
+	}
+
+	obj := shard.allocator()
 	obj.shardIndex = shardIndex
 	return obj
 }
@@ -429,6 +487,10 @@ func (p *ShardedChannelBasedPool) Put(obj *testObject) {
 // Sharded Atomic Based Pool
 type ShardedAtomicBasedPool struct {
 	shards []*AtomicBasedPool
+
+	// victims and ticker are only set once EnableGCVictimCache has been called.
+	victims []atomic.Pointer[AtomicBasedPool]
+	ticker  *gcTicker
 }
 
 func NewShardedAtomicBasedPool(capacity int, numShards int, allocator func() *testObject, cleaner func(*testObject)) *ShardedAtomicBasedPool {
@@ -453,7 +515,21 @@ func (p *ShardedAtomicBasedPool) Get() *testObject {
 	shard := p.shards[shardIndex]
 	runtimeProcUnpin()
 
-	obj := shard.Get()
+	if obj, ok := shard.tryGet(); ok {
+		obj.shardIndex = shardIndex
+		return obj
+	}
+
+	if p.victims != nil {
+		if victim := p.victims[shardIndex].Load(); victim != nil {
+			if obj, ok := victim.tryGet(); ok {
+				obj.shardIndex = shardIndex
+				return obj
+			}
+		}
+	}
+
+	obj := shard.allocator()
 	obj.shardIndex = shardIndex
 	return obj
 }
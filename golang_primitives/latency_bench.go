@@ -0,0 +1,168 @@
+package benchs
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// pool is the minimal interface runPoolLatencyBenchmark needs from any of
+// the *testObject pool implementations in this package.
+type pool interface {
+	Get() *testObject
+	Put(*testObject)
+}
+
+// latencyWorkload configures the access pattern runPoolLatencyBenchmark
+// drives against a pool.
+type latencyWorkload struct {
+	// HoldTime simulates work done between Get and Put; zero means
+	// Put happens immediately after Get.
+	HoldTime time.Duration
+	// Imbalance, when true, splits goroutines into Get-only and Put-only
+	// roles instead of each goroutine doing both. This is the case that
+	// breaks runtimeProcPin-based sharding, since objects end up being
+	// returned to a different P's shard than the one they were drawn from.
+	Imbalance bool
+}
+
+const (
+	latencyBuckets  = 2048
+	latencyBucketNs = 50 // width of one bucket; the last bucket is an overflow catch-all
+)
+
+// latencyHistogram is a fixed-bucket, lock-free latency histogram: every
+// goroutine gets its own instance and increments it directly, so recording a
+// sample costs a single non-atomic counter bump.
+type latencyHistogram struct {
+	buckets [latencyBuckets]uint64
+}
+
+func (h *latencyHistogram) record(d time.Duration) {
+	idx := int(d.Nanoseconds() / latencyBucketNs)
+	if idx >= latencyBuckets {
+		idx = latencyBuckets - 1
+	}
+	h.buckets[idx]++
+}
+
+func (h *latencyHistogram) merge(other *latencyHistogram) {
+	for i := range h.buckets {
+		h.buckets[i] += other.buckets[i]
+	}
+}
+
+// percentile returns the approximate latency at p (0-100), accurate to one
+// bucket width.
+func (h *latencyHistogram) percentile(p float64) time.Duration {
+	var total uint64
+	for _, c := range h.buckets {
+		total += c
+	}
+	if total == 0 {
+		return 0
+	}
+
+	target := uint64(float64(total) * p / 100)
+	var cumulative uint64
+	for i, c := range h.buckets {
+		cumulative += c
+		if cumulative >= target {
+			return time.Duration(i*latencyBucketNs) * time.Nanosecond
+		}
+	}
+	return time.Duration((latencyBuckets-1)*latencyBucketNs) * time.Nanosecond
+}
+
+// runPoolLatencyBenchmark drives p with workload and reports p50/p90/p99/p999
+// op latencies instead of just throughput. Pure ns/op hides exactly the
+// failure modes this is meant to expose: a pool that looks great on
+// throughput but has a long tail under contention, or a sharded pool whose
+// objects end up on the wrong shard under an imbalanced Get/Put split.
+func runPoolLatencyBenchmark(b *testing.B, p pool, workload latencyWorkload) {
+	debug.SetGCPercent(-1)
+	b.ReportAllocs()
+
+	numGoroutines := runtime.GOMAXPROCS(0)
+	imbalance := workload.Imbalance && numGoroutines >= 2
+
+	histograms := make([]latencyHistogram, numGoroutines)
+	var nextID atomic.Int64
+
+	var pending chan *testObject
+	var getters sync.WaitGroup
+	if imbalance {
+		pending = make(chan *testObject, numGoroutines*4)
+
+		// Getters and putters draw from the same b.N budget via pb.Next(),
+		// but nothing guarantees they exhaust it in lockstep - a putter can
+		// run out of iterations while getters still have objects in flight,
+		// which would leave those sends blocked forever on a full, never-
+		// drained channel. So only getters drive pb.Next(); putters instead
+		// range over pending until it's closed, which happens once every
+		// getter has returned.
+		numGetters := (numGoroutines + 1) / 2
+		getters.Add(numGetters)
+		go func() {
+			getters.Wait()
+			close(pending)
+		}()
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		id := int(nextID.Add(1)-1) % numGoroutines
+		hist := &histograms[id]
+
+		if !imbalance {
+			for pb.Next() {
+				start := time.Now()
+				obj := p.Get()
+				hist.record(time.Since(start))
+
+				if workload.HoldTime > 0 {
+					time.Sleep(workload.HoldTime)
+				}
+
+				start = time.Now()
+				p.Put(obj)
+				hist.record(time.Since(start))
+			}
+			return
+		}
+
+		if id%2 == 0 {
+			for pb.Next() {
+				start := time.Now()
+				obj := p.Get()
+				hist.record(time.Since(start))
+
+				if workload.HoldTime > 0 {
+					time.Sleep(workload.HoldTime)
+				}
+				pending <- obj
+			}
+			getters.Done()
+			return
+		}
+
+		for obj := range pending {
+			start := time.Now()
+			p.Put(obj)
+			hist.record(time.Since(start))
+		}
+	})
+
+	merged := &latencyHistogram{}
+	for i := range histograms {
+		merged.merge(&histograms[i])
+	}
+
+	b.ReportMetric(float64(merged.percentile(50).Nanoseconds()), "p50-ns/op")
+	b.ReportMetric(float64(merged.percentile(90).Nanoseconds()), "p90-ns/op")
+	b.ReportMetric(float64(merged.percentile(99).Nanoseconds()), "p99-ns/op")
+	b.ReportMetric(float64(merged.percentile(99.9).Nanoseconds()), "p999-ns/op")
+}
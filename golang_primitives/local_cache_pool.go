@@ -0,0 +1,136 @@
+package benchs
+
+import (
+	"runtime"
+	"sync"
+)
+
+// LocalCachePool is a two-tier pool inspired by sync.Pool's per-P design: each
+// P owns a small local cache that is pushed to and popped from without locks
+// or atomics (guarded only by runtimeProcPin/runtimeProcUnpin), backed by a
+// shared, mutex-protected global cache that local caches spill to and refill
+// from in fixed-size batches.
+type LocalCachePool struct {
+	locals    []localCache
+	global    []*testObject
+	globalMu  sync.Mutex
+	batchSize int
+	allocator func() *testObject
+	cleaner   func(*testObject)
+}
+
+type localCache struct {
+	objects []*testObject
+}
+
+// NewLocalCachePool creates a pool with one local cache per P, each holding
+// up to localCap objects, and a shared global cache that an empty local
+// cache refills from in batches of up to batchSize and a full local cache
+// spills half its contents to.
+func NewLocalCachePool(localCap, batchSize int, allocator func() *testObject, cleaner func(*testObject)) *LocalCachePool {
+	locals := make([]localCache, runtime.GOMAXPROCS(0))
+	for i := range locals {
+		locals[i].objects = make([]*testObject, 0, localCap)
+	}
+
+	return &LocalCachePool{
+		locals:    locals,
+		batchSize: batchSize,
+		allocator: allocator,
+		cleaner:   cleaner,
+	}
+}
+
+func (p *LocalCachePool) Get() *testObject {
+	pid := runtimeProcPin()
+	local := &p.locals[pid]
+
+	if n := len(local.objects); n > 0 {
+		obj := local.objects[n-1]
+		local.objects = local.objects[:n-1]
+		runtimeProcUnpin()
+		return obj
+	}
+	runtimeProcUnpin()
+
+	batch := p.takeBatch()
+	if len(batch) == 0 {
+		return p.allocator()
+	}
+
+	// Keep one for the caller, stash as much of the rest as fits in our
+	// local cache, and hand any overflow straight back to the global
+	// cache instead of growing the local slice past its fixed capacity.
+	obj := batch[len(batch)-1]
+	batch = batch[:len(batch)-1]
+
+	pid = runtimeProcPin()
+	local = &p.locals[pid]
+	room := cap(local.objects) - len(local.objects)
+	if room > len(batch) {
+		room = len(batch)
+	}
+	local.objects = append(local.objects, batch[:room]...)
+	overflow := batch[room:]
+	runtimeProcUnpin()
+
+	if len(overflow) > 0 {
+		p.globalMu.Lock()
+		p.global = append(p.global, overflow...)
+		p.globalMu.Unlock()
+	}
+
+	return obj
+}
+
+// takeBatch moves up to p.batchSize objects from the global cache to the
+// caller under the global lock.
+func (p *LocalCachePool) takeBatch() []*testObject {
+	p.globalMu.Lock()
+	defer p.globalMu.Unlock()
+
+	n := min(p.batchSize, len(p.global))
+	if n == 0 {
+		return nil
+	}
+
+	batch := append([]*testObject(nil), p.global[len(p.global)-n:]...)
+	p.global = p.global[:len(p.global)-n]
+	return batch
+}
+
+func (p *LocalCachePool) Put(obj *testObject) {
+	pid := runtimeProcPin()
+	local := &p.locals[pid]
+
+	if len(local.objects) < cap(local.objects) {
+		local.objects = append(local.objects, obj)
+		runtimeProcUnpin()
+		return
+	}
+
+	// Local cache is full: move half of it to the global cache so the
+	// fast path keeps room to push into. A capacity-1 cache has no "half"
+	// to evict (half == 0), so send everything including obj to global
+	// instead of appending anyway and growing the slice past its capacity.
+	half := len(local.objects) / 2
+	if half == 0 {
+		overflow := append(append([]*testObject(nil), local.objects...), obj)
+		local.objects = local.objects[:0]
+		runtimeProcUnpin()
+
+		p.globalMu.Lock()
+		p.global = append(p.global, overflow...)
+		p.globalMu.Unlock()
+		return
+	}
+
+	overflow := append([]*testObject(nil), local.objects[:half]...)
+	local.objects = append(local.objects[:0], local.objects[half:]...)
+	local.objects = append(local.objects, obj)
+	runtimeProcUnpin()
+
+	p.globalMu.Lock()
+	p.global = append(p.global, overflow...)
+	p.globalMu.Unlock()
+}
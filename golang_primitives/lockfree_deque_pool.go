@@ -0,0 +1,198 @@
+package benchs
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// chaseLevDeque is a fixed-capacity work-stealing deque: only the owning
+// goroutine may push/pop from the bottom, while any goroutine may steal from
+// the top. The top index is packed together with an ABA-guarding tag into a
+// single atomic word so a steal can be resolved with one CompareAndSwap
+// instead of a true double-wide CAS on (top, tag).
+type chaseLevDeque struct {
+	buffer []atomic.Pointer[testObject]
+	bottom atomic.Int64
+	top    atomic.Uint64
+}
+
+func newChaseLevDeque(capacity int) *chaseLevDeque {
+	return &chaseLevDeque{buffer: make([]atomic.Pointer[testObject], capacity)}
+}
+
+func packTop(index, tag uint32) uint64 {
+	return uint64(tag)<<32 | uint64(index)
+}
+
+func unpackTop(v uint64) (index, tag uint32) {
+	return uint32(v), uint32(v >> 32)
+}
+
+// pushBottom is only safe to call from the owning goroutine.
+func (d *chaseLevDeque) pushBottom(obj *testObject) bool {
+	b := d.bottom.Load()
+	t, _ := unpackTop(d.top.Load())
+	if b-int64(t) >= int64(len(d.buffer)) {
+		return false // full
+	}
+
+	d.buffer[b%int64(len(d.buffer))].Store(obj)
+	d.bottom.Store(b + 1)
+	return true
+}
+
+// popBottom is only safe to call from the owning goroutine.
+func (d *chaseLevDeque) popBottom() (*testObject, bool) {
+	b := d.bottom.Load() - 1
+	d.bottom.Store(b)
+
+	topVal := d.top.Load()
+	t, tag := unpackTop(topVal)
+
+	if int64(t) > b {
+		// Deque was already empty; restore bottom and bail.
+		d.bottom.Store(b + 1)
+		return nil, false
+	}
+
+	obj := d.buffer[b%int64(len(d.buffer))].Load()
+	if int64(t) == b {
+		// Last element: race with stealers for it.
+		if !d.top.CompareAndSwap(topVal, packTop(t+1, tag+1)) {
+			obj = nil
+		}
+		d.bottom.Store(b + 1)
+		return obj, obj != nil
+	}
+
+	return obj, true
+}
+
+// steal may be called by any goroutine.
+func (d *chaseLevDeque) steal() (*testObject, bool) {
+	topVal := d.top.Load()
+	t, tag := unpackTop(topVal)
+	b := d.bottom.Load()
+
+	if int64(t) >= b {
+		return nil, false // empty
+	}
+
+	obj := d.buffer[uint64(t)%uint64(len(d.buffer))].Load()
+	if !d.top.CompareAndSwap(topVal, packTop(t+1, tag+1)) {
+		return nil, false // lost the race with another stealer or the owner
+	}
+	return obj, true
+}
+
+// LockFreeDequePool replaces the CAS-on-index scheme of AtomicBasedPool with
+// one work-stealing deque per shard: the owning P (pinned via
+// runtimeProcPin) pushes and pops from the bottom with atomic loads/stores
+// (plus one CompareAndSwap when popping the last element), while a starved
+// shard steals from the top of another shard's deque instead of falling
+// straight through to the allocator.
+type LockFreeDequePool struct {
+	shards    []*chaseLevDeque
+	allocator func() *testObject
+	cleaner   func(*testObject)
+
+	// overflow catches objects that can't go back onto the current P's
+	// shard because stealing has pushed that shard past its fixed
+	// capacity. pushBottom is owner-only, so a full shard can't be
+	// relieved by pushing onto some other shard we don't own; overflow is
+	// the shared fallback instead. Get checks it once stealing comes up
+	// dry.
+	overflowMu sync.Mutex
+	overflow   []*testObject
+}
+
+func NewLockFreeDequePool(capacity, numShards int, allocator func() *testObject, cleaner func(*testObject)) *LockFreeDequePool {
+	if numShards <= 0 {
+		numShards = runtime.GOMAXPROCS(0)
+	}
+
+	shardCapacity := max(capacity/numShards, 1)
+	shards := make([]*chaseLevDeque, numShards)
+	for i := range shards {
+		shards[i] = newChaseLevDeque(shardCapacity)
+		for range shardCapacity {
+			shards[i].pushBottom(allocator())
+		}
+	}
+
+	return &LockFreeDequePool{
+		shards:    shards,
+		allocator: allocator,
+		cleaner:   cleaner,
+	}
+}
+
+func (p *LockFreeDequePool) Get() *testObject {
+	// popBottom is owner-only: stay pinned to this P for the whole call so
+	// no other goroutine can run on it and race the bottom-index update.
+	// Shard i is owned by P i alone; a P with no shard of its own (more Ps
+	// than shards) skips straight to stealing.
+	pid := runtimeProcPin()
+	shardIndex := pid % len(p.shards)
+	var obj *testObject
+	var ok bool
+	if pid < len(p.shards) {
+		obj, ok = p.shards[shardIndex].popBottom()
+	}
+	runtimeProcUnpin()
+
+	if ok {
+		obj.shardIndex = shardIndex
+		return obj
+	}
+
+	// Our shard is dry (or we have none): try to steal before allocating.
+	for i := range len(p.shards) {
+		victim := p.shards[(shardIndex+i)%len(p.shards)]
+		if obj, ok := victim.steal(); ok {
+			obj.shardIndex = shardIndex
+			return obj
+		}
+	}
+
+	// Nothing to steal either: check the overflow before paying for a
+	// fresh allocation.
+	p.overflowMu.Lock()
+	if n := len(p.overflow); n > 0 {
+		obj := p.overflow[n-1]
+		p.overflow = p.overflow[:n-1]
+		p.overflowMu.Unlock()
+		obj.shardIndex = shardIndex
+		return obj
+	}
+	p.overflowMu.Unlock()
+
+	obj = p.allocator()
+	obj.shardIndex = shardIndex
+	return obj
+}
+
+func (p *LockFreeDequePool) Put(obj *testObject) {
+	// pushBottom is owner-only, and the only shard we're currently the
+	// owner of is our own P's - not necessarily the shard obj was drawn
+	// from (it may have been stolen). Pin and push there, same as Get.
+	pid := runtimeProcPin()
+	ok := false
+	if pid < len(p.shards) {
+		ok = p.shards[pid].pushBottom(obj)
+	}
+	runtimeProcUnpin()
+	if ok {
+		return
+	}
+
+	// Our shard is already at capacity (steals can pile extra objects
+	// onto whichever shard happens to be the owner at Put time), or this
+	// P owns no shard at all. We can't
+	// push onto a shard we don't own, so stash obj in the overflow
+	// instead of dropping it.
+	p.overflowMu.Lock()
+	p.overflow = append(p.overflow, obj)
+	p.overflowMu.Unlock()
+}
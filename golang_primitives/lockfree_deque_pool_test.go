@@ -0,0 +1,79 @@
+package benchs
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestLockFreeDequePool_NoDuplicateOrLost hammers a LockFreeDequePool from
+// many goroutines (run this with -race) and checks that no object is ever
+// handed out to two goroutines at once and that nothing vanishes from the
+// pool in the process.
+func TestLockFreeDequePool_NoDuplicateOrLost(t *testing.T) {
+	const numShards = 4
+	const perShardCapacity = 64
+	const capacity = numShards * perShardCapacity
+
+	var nextID atomic.Int64
+	owned := make([]atomic.Int32, 1<<20)
+
+	alloc := func() *testObject {
+		id := nextID.Add(1) - 1
+		return &testObject{ID: int(id), shardIndex: -1}
+	}
+	clean := func(*testObject) {}
+
+	pool := NewLockFreeDequePool(capacity, numShards, alloc, clean)
+
+	const goroutines = 16
+	const iterations = 5000
+
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for range goroutines {
+		go func() {
+			defer wg.Done()
+			for range iterations {
+				obj := pool.Get()
+				if !owned[obj.ID].CompareAndSwap(0, 1) {
+					t.Errorf("object %d handed out while already checked out", obj.ID)
+				}
+				if !owned[obj.ID].CompareAndSwap(1, 0) {
+					t.Errorf("object %d lost track of its checked-out state on Put", obj.ID)
+				}
+				pool.Put(obj)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Drain every shard, plus the overflow list, and make sure each
+	// surviving object appears exactly once.
+	seen := make(map[int]bool)
+	total := 0
+	for _, shard := range pool.shards {
+		for {
+			obj, ok := shard.popBottom()
+			if !ok {
+				break
+			}
+			if seen[obj.ID] {
+				t.Errorf("object %d duplicated across shards after drain", obj.ID)
+			}
+			seen[obj.ID] = true
+			total++
+		}
+	}
+	for _, obj := range pool.overflow {
+		if seen[obj.ID] {
+			t.Errorf("object %d duplicated in overflow after drain", obj.ID)
+		}
+		seen[obj.ID] = true
+		total++
+	}
+
+	if total < capacity {
+		t.Errorf("lost objects: drained %d, want at least %d", total, capacity)
+	}
+}
@@ -0,0 +1,367 @@
+package benchs
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PoolKind selects which underlying pool implementation backs a size class
+// in a SizeClassedBufferPool, or the storage strategy behind a generic Pool[T].
+type PoolKind int
+
+const (
+	// MutexRingBufferKind backs a pool with a mutex-protected ring buffer.
+	MutexRingBufferKind PoolKind = iota
+	// AtomicIndexKind backs a pool with a CAS-protected index, mirroring AtomicBasedPool.
+	AtomicIndexKind
+	// ChannelKind backs a pool with a buffered channel, mirroring ChannelBasedPool.
+	ChannelKind
+	// CondKind backs a pool with a condition variable, mirroring CondBasedPool.
+	CondKind
+)
+
+// byteClassPool is the minimal interface a size class needs from whichever
+// pool implementation backs it.
+type byteClassPool interface {
+	get() ([]byte, bool)
+	put(buf []byte) bool
+}
+
+// mutexRingByteClass backs a size class with a mutex-protected ring buffer,
+// mirroring MutexRingBufferPool but for []byte instead of *testObject.
+type mutexRingByteClass struct {
+	rb *RingBuffer[[]byte]
+	mu sync.Mutex
+}
+
+func newMutexRingByteClass(poolSize, bufCap int) *mutexRingByteClass {
+	c := &mutexRingByteClass{rb: NewRingBuffer[[]byte](poolSize)}
+	for range poolSize {
+		c.rb.Push(make([]byte, 0, bufCap))
+	}
+	return c
+}
+
+func (c *mutexRingByteClass) get() ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rb.Pop()
+}
+
+func (c *mutexRingByteClass) put(buf []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rb.Push(buf[:0])
+}
+
+// atomicIndexByteClass backs a size class with a CAS-protected index over a
+// fixed slice of buffers, mirroring AtomicBasedPool but for []byte instead
+// of *testObject.
+type atomicIndexByteClass struct {
+	buffers  [][]byte
+	index    atomic.Int64
+	capacity int64
+}
+
+func newAtomicIndexByteClass(poolSize, bufCap int) *atomicIndexByteClass {
+	c := &atomicIndexByteClass{buffers: make([][]byte, poolSize), capacity: int64(poolSize)}
+	for i := range c.buffers {
+		c.buffers[i] = make([]byte, 0, bufCap)
+	}
+	c.index.Store(int64(poolSize))
+	return c
+}
+
+func (c *atomicIndexByteClass) get() ([]byte, bool) {
+	for {
+		idx := c.index.Load()
+		if idx <= 0 {
+			return nil, false
+		}
+		if c.index.CompareAndSwap(idx, idx-1) {
+			return c.buffers[idx-1], true
+		}
+	}
+}
+
+func (c *atomicIndexByteClass) put(buf []byte) bool {
+	for {
+		idx := c.index.Load()
+		if idx >= c.capacity {
+			return false
+		}
+		if c.index.CompareAndSwap(idx, idx+1) {
+			c.buffers[idx] = buf[:0]
+			return true
+		}
+	}
+}
+
+// channelByteClass backs a size class with a buffered channel, mirroring
+// ChannelBasedPool but for []byte instead of *testObject.
+type channelByteClass struct {
+	buffers chan []byte
+}
+
+func newChannelByteClass(poolSize, bufCap int) *channelByteClass {
+	c := &channelByteClass{buffers: make(chan []byte, poolSize)}
+	for range poolSize {
+		c.buffers <- make([]byte, 0, bufCap)
+	}
+	return c
+}
+
+func (c *channelByteClass) get() ([]byte, bool) {
+	select {
+	case buf := <-c.buffers:
+		return buf, true
+	default:
+		return nil, false
+	}
+}
+
+func (c *channelByteClass) put(buf []byte) bool {
+	select {
+	case c.buffers <- buf[:0]:
+		return true
+	default:
+		return false
+	}
+}
+
+// condByteClass backs a size class with CondBasedPool's mutex-protected
+// slice storage. Unlike CondBasedPool.Get, get never calls cond.Wait: a
+// size class is always one of several tried in turn by
+// SizeClassedBufferPool.Get, which falls through to the next class (or an
+// unpooled allocation) on a miss rather than blocking, so there's nothing
+// here for a wait to usefully block on.
+type condByteClass struct {
+	buffers [][]byte
+	mu      sync.Mutex
+}
+
+func newCondByteClass(poolSize, bufCap int) *condByteClass {
+	c := &condByteClass{buffers: make([][]byte, 0, poolSize)}
+	for range poolSize {
+		c.buffers = append(c.buffers, make([]byte, 0, bufCap))
+	}
+	return c
+}
+
+func (c *condByteClass) get() ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.buffers) == 0 {
+		return nil, false
+	}
+
+	buf := c.buffers[len(c.buffers)-1]
+	c.buffers = c.buffers[:len(c.buffers)-1]
+	return buf, true
+}
+
+func (c *condByteClass) put(buf []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.buffers) >= cap(c.buffers) {
+		return false
+	}
+
+	c.buffers = append(c.buffers, buf[:0])
+	return true
+}
+
+// sizeClass pools []byte buffers of a single capacity and tracks how that
+// capacity compares to what callers actually asked for.
+type sizeClass struct {
+	capacity int
+	pool     byteClassPool
+
+	hits    atomic.Uint32
+	misses  atomic.Uint32
+	equal   atomic.Uint32
+	half    atomic.Uint32
+	greater atomic.Uint32
+}
+
+func newSizeClass(capacity, poolSize int, kind PoolKind) *sizeClass {
+	var pool byteClassPool
+	switch kind {
+	case AtomicIndexKind:
+		pool = newAtomicIndexByteClass(poolSize, capacity)
+	case ChannelKind:
+		pool = newChannelByteClass(poolSize, capacity)
+	case CondKind:
+		pool = newCondByteClass(poolSize, capacity)
+	default:
+		pool = newMutexRingByteClass(poolSize, capacity)
+	}
+
+	return &sizeClass{capacity: capacity, pool: pool}
+}
+
+// record classifies how this class's fixed capacity compares to n, the size
+// the caller actually requested, after a successful Get. The caller only
+// ever reaches a class whose capacity is already >= n, so there's no "too
+// small" case to track here.
+func (c *sizeClass) record(n int) {
+	switch {
+	case c.capacity == n:
+		c.equal.Add(1)
+	case c.capacity > 2*n:
+		c.greater.Add(1)
+	default:
+		c.half.Add(1)
+	}
+}
+
+// oversized reports whether this class has consistently handed back buffers
+// more than 2x the size callers asked for.
+func (c *sizeClass) oversized() bool {
+	hits := c.hits.Load()
+	if hits == 0 {
+		return false
+	}
+	return c.greater.Load()*2 > hits
+}
+
+// SizeClassedBufferPool pools []byte buffers across several fixed size
+// classes instead of a single capacity, routing a request for n bytes to
+// the smallest class whose capacity is >= n. A background goroutine
+// periodically recomputes the boundary between the smallest class and the
+// unpooled path, demoting classes that consistently hand back buffers more
+// than 2x the requested size.
+type SizeClassedBufferPool struct {
+	classes  []*sizeClass
+	boundary atomic.Int64 // index of the first still-pooled class
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+type sizeClassConfig struct {
+	kind PoolKind
+}
+
+// SizeClassOption configures how a SizeClassedBufferPool's classes are built.
+type SizeClassOption func(*sizeClassConfig)
+
+// WithPoolKind selects the pool implementation backing every size class.
+func WithPoolKind(kind PoolKind) SizeClassOption {
+	return func(c *sizeClassConfig) { c.kind = kind }
+}
+
+// NewSizeClassedBufferPool builds one size class per entry in classSizes
+// (ascending), each pre-populated with poolSize buffers. If
+// rebalanceInterval is positive, a background goroutine re-evaluates the
+// unpooled boundary on that interval until Close is called.
+func NewSizeClassedBufferPool(classSizes []int, poolSize int, rebalanceInterval time.Duration, opts ...SizeClassOption) *SizeClassedBufferPool {
+	cfg := sizeClassConfig{kind: MutexRingBufferKind}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	classes := make([]*sizeClass, len(classSizes))
+	for i, size := range classSizes {
+		classes[i] = newSizeClass(size, poolSize, cfg.kind)
+	}
+
+	p := &SizeClassedBufferPool{
+		classes: classes,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	if rebalanceInterval > 0 {
+		go p.rebalanceLoop(rebalanceInterval)
+	} else {
+		close(p.done)
+	}
+
+	return p
+}
+
+// Get returns a buffer able to hold at least n bytes, routed to the smallest
+// still-pooled class whose capacity is >= n, or allocated directly if n
+// falls below the current unpooled boundary or above every class.
+func (p *SizeClassedBufferPool) Get(n int) []byte {
+	boundary := int(p.boundary.Load())
+	if boundary > 0 && n <= p.classes[boundary-1].capacity {
+		return make([]byte, 0, n)
+	}
+
+	for i := boundary; i < len(p.classes); i++ {
+		class := p.classes[i]
+		if class.capacity < n {
+			continue
+		}
+
+		if buf, ok := class.pool.get(); ok {
+			class.hits.Add(1)
+			class.record(n)
+			return buf
+		}
+
+		class.misses.Add(1)
+		return make([]byte, 0, class.capacity)
+	}
+
+	return make([]byte, 0, n)
+}
+
+// Put returns buf to the size class matching its capacity, if any. Classes
+// below the current unpooled boundary have been demoted and no longer take
+// buffers back - returning one there would let a demoted class quietly grow
+// again instead of staying off the pooled path. Buffers that don't match any
+// still-pooled class capacity are simply dropped.
+func (p *SizeClassedBufferPool) Put(buf []byte) {
+	c := cap(buf)
+	boundary := int(p.boundary.Load())
+	for i := boundary; i < len(p.classes); i++ {
+		class := p.classes[i]
+		if class.capacity == c {
+			class.pool.put(buf)
+			return
+		}
+	}
+}
+
+// Close stops the background rebalancing goroutine.
+func (p *SizeClassedBufferPool) Close() error {
+	close(p.stop)
+	<-p.done
+	return nil
+}
+
+func (p *SizeClassedBufferPool) rebalanceLoop(interval time.Duration) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.rebalance()
+		}
+	}
+}
+
+// rebalance demotes the smallest still-pooled class to the unpooled path
+// once it is consistently returning buffers more than 2x the size callers
+// asked for.
+func (p *SizeClassedBufferPool) rebalance() {
+	boundary := int(p.boundary.Load())
+	if boundary >= len(p.classes) {
+		return
+	}
+
+	if p.classes[boundary].oversized() {
+		p.boundary.Store(int64(boundary + 1))
+	}
+}